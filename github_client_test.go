@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/wimwenigerkind/github-release-monitor/metrics"
+)
+
+func TestGitHubAppConfigEnabled(t *testing.T) {
+	cases := []struct {
+		name string
+		cfg  GitHubAppConfig
+		want bool
+	}{
+		{"all fields set", GitHubAppConfig{AppID: 1, InstallationID: 2, PrivateKeyPath: "key.pem"}, true},
+		{"zero value", GitHubAppConfig{}, false},
+		{"missing app id", GitHubAppConfig{InstallationID: 2, PrivateKeyPath: "key.pem"}, false},
+		{"missing installation id", GitHubAppConfig{AppID: 1, PrivateKeyPath: "key.pem"}, false},
+		{"missing private key path", GitHubAppConfig{AppID: 1, InstallationID: 2}, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := c.cfg.enabled(); got != c.want {
+				t.Errorf("enabled() = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestRestBaseURL(t *testing.T) {
+	cases := []struct {
+		enterpriseURL string
+		want          string
+	}{
+		{"https://ghe.company.com/api/v3", "https://ghe.company.com/api/v3"},
+		{"https://ghe.company.com/api/v3/", "https://ghe.company.com/api/v3"},
+	}
+
+	for _, c := range cases {
+		if got := restBaseURL(c.enterpriseURL); got != c.want {
+			t.Errorf("restBaseURL(%q) = %q, want %q", c.enterpriseURL, got, c.want)
+		}
+	}
+}
+
+func TestGraphQLURL(t *testing.T) {
+	cases := []struct {
+		enterpriseURL string
+		want          string
+	}{
+		{"https://ghe.company.com/api/v3", "https://ghe.company.com/api/graphql"},
+		{"https://ghe.company.com/api/v3/", "https://ghe.company.com/api/graphql"},
+		{"https://ghe.company.com", "https://ghe.company.com/api/graphql"},
+	}
+
+	for _, c := range cases {
+		if got := graphQLURL(c.enterpriseURL); got != c.want {
+			t.Errorf("graphQLURL(%q) = %q, want %q", c.enterpriseURL, got, c.want)
+		}
+	}
+}
+
+func TestCreateGithubClientUnauthenticated(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(testLogWriter{t}, nil))
+	client, rlTransport, err := createGithubClient(context.Background(), logger, Config{}, metrics.New())
+	if err != nil {
+		t.Fatalf("createGithubClient returned error: %v", err)
+	}
+	if client == nil {
+		t.Error("expected a non-nil client")
+	}
+	if rlTransport == nil {
+		t.Error("expected a non-nil rate-limit transport")
+	}
+}
+
+func TestCreateGithubClientInvalidGitHubAppPrivateKey(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(testLogWriter{t}, nil))
+	config := Config{
+		GitHubApp: GitHubAppConfig{AppID: 1, InstallationID: 2, PrivateKeyPath: "/nonexistent/key.pem"},
+	}
+
+	_, _, err := createGithubClient(context.Background(), logger, config, metrics.New())
+	if err == nil {
+		t.Fatal("expected an error for a missing private key file")
+	}
+}
+
+// testLogWriter discards log output so tests stay quiet, while still
+// exercising the real slog.Logger code path in createGithubClient.
+type testLogWriter struct{ t *testing.T }
+
+func (w testLogWriter) Write(p []byte) (int, error) {
+	return len(p), nil
+}
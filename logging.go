@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// LogConfig configures the application logger from config.yml's `log:`
+// block.
+type LogConfig struct {
+	Level  string `yaml:"level,omitempty"`
+	Format string `yaml:"format,omitempty"`
+	Output string `yaml:"output,omitempty"`
+}
+
+// newLogger builds the application's slog.Logger from cfg, with
+// levelOverride (typically from --log-level) winning over cfg.Level when
+// set. The returned close func flushes/closes the log output and must be
+// deferred by the caller; it's a no-op for stdout/stderr.
+func newLogger(cfg LogConfig, levelOverride string) (logger *slog.Logger, close func() error, err error) {
+	level := cfg.Level
+	if levelOverride != "" {
+		level = levelOverride
+	}
+
+	slogLevel, err := parseLogLevel(level)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	output, closeFn, err := openLogOutput(cfg.Output)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	format := cfg.Format
+	if format == "" {
+		format = defaultLogFormat()
+	}
+
+	opts := &slog.HandlerOptions{Level: slogLevel}
+
+	var handler slog.Handler
+	switch format {
+	case "json":
+		handler = slog.NewJSONHandler(output, opts)
+	case "text":
+		handler = slog.NewTextHandler(output, opts)
+	default:
+		return nil, nil, fmt.Errorf("unknown log format %q", format)
+	}
+
+	return slog.New(handler), closeFn, nil
+}
+
+func parseLogLevel(level string) (slog.Level, error) {
+	switch strings.ToLower(level) {
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "debug":
+		return slog.LevelDebug, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return 0, fmt.Errorf("unknown log level %q", level)
+	}
+}
+
+func openLogOutput(output string) (io.Writer, func() error, error) {
+	switch output {
+	case "", "stdout":
+		return os.Stdout, func() error { return nil }, nil
+	case "stderr":
+		return os.Stderr, func() error { return nil }, nil
+	default:
+		f, err := os.OpenFile(output, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error opening log output %q: %w", output, err)
+		}
+		return f, f.Close, nil
+	}
+}
+
+// defaultLogFormat emits JSON when stdout isn't a terminal (e.g. running
+// under a container, where log aggregators expect structured output) and
+// human-readable text otherwise.
+func defaultLogFormat() string {
+	if fi, err := os.Stdout.Stat(); err == nil && fi.Mode()&os.ModeCharDevice == 0 {
+		return "json"
+	}
+	return "text"
+}
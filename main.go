@@ -2,7 +2,10 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
+	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
 	"strings"
@@ -10,29 +13,71 @@ import (
 	"syscall"
 	"time"
 
-	"github.com/containrrr/shoutrrr"
-	"github.com/google/go-github/github"
+	"github.com/bradleyfalzon/ghinstallation/v2"
+	"github.com/shurcooL/githubv4"
 	"golang.org/x/oauth2"
 	"gopkg.in/yaml.v3"
+
+	"github.com/wimwenigerkind/github-release-monitor/metrics"
+	"github.com/wimwenigerkind/github-release-monitor/notifier"
+	"github.com/wimwenigerkind/github-release-monitor/state"
 )
 
 type Config struct {
-	AccessToken   string         `yaml:"access_token,omitempty"`
-	Interval      int            `yaml:"interval"`
-	Repositories  []Repository   `yaml:"repositories"`
-	Notifications []Notification `yaml:"notifications"`
+	AccessToken   string          `yaml:"access_token,omitempty"`
+	Interval      int             `yaml:"interval"`
+	StateBackend  string          `yaml:"state_backend,omitempty"`
+	StatePath     string          `yaml:"state_path,omitempty"`
+	Concurrency   int             `yaml:"concurrency,omitempty"`
+	EnterpriseURL string          `yaml:"enterprise_url,omitempty"`
+	GitHubApp     GitHubAppConfig `yaml:"github_app,omitempty"`
+	Log           LogConfig       `yaml:"log,omitempty"`
+	Metrics       MetricsConfig   `yaml:"metrics,omitempty"`
+	Repositories  []Repository    `yaml:"repositories"`
+	Notifications []Notification  `yaml:"notifications"`
+}
+
+// GitHubAppConfig authenticates as a GitHub App installation instead of a
+// personal access token, so the monitor can run under a bot identity with
+// its own (much higher) rate limit and without anyone rotating a token.
+type GitHubAppConfig struct {
+	AppID          int64  `yaml:"app_id,omitempty"`
+	InstallationID int64  `yaml:"installation_id,omitempty"`
+	PrivateKeyPath string `yaml:"private_key_path,omitempty"`
+}
+
+// enabled reports whether all fields required to mint installation tokens
+// are set.
+func (c GitHubAppConfig) enabled() bool {
+	return c.AppID != 0 && c.InstallationID != 0 && c.PrivateKeyPath != ""
+}
+
+// MetricsConfig configures the Prometheus/health HTTP server started in
+// daemon mode.
+type MetricsConfig struct {
+	Listen string `yaml:"listen,omitempty"`
 }
 
 type Repository struct {
-	Slug              string `yaml:"slug"`
-	CurrentReleaseTag string `yaml:"current_release_tag"`
+	Slug               string `yaml:"slug"`
+	IncludePrereleases bool   `yaml:"include_prereleases,omitempty"`
+	IncludeDrafts      bool   `yaml:"include_drafts,omitempty"`
+	TagRegex           string `yaml:"tag_regex,omitempty"`
+	MaxHistory         int    `yaml:"max_history,omitempty"`
 }
 
 type Notification struct {
-	RawURL string `yaml:"url"`
+	Type     string            `yaml:"type,omitempty"`
+	RawURL   string            `yaml:"url"`
+	Template string            `yaml:"template,omitempty"`
+	Filters  *notifier.Filters `yaml:"filters,omitempty"`
 }
 
 func main() {
+	migrateConfigFlag := flag.Bool("migrate-config", false, "move current_release_tag values from config.yml into the state store, then exit")
+	logLevelFlag := flag.String("log-level", "", "override the configured log level (debug, info, warn, error)")
+	flag.Parse()
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
@@ -47,13 +92,41 @@ func main() {
 		return
 	}
 
-	client := createGithubClient(ctx, *config)
+	logger, closeLogger, err := newLogger(config.Log, *logLevelFlag)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Error configuring logging: %v\n", err)
+		return
+	}
+	defer closeLogger()
+
+	store, err := state.New(config.StateBackend, config.StatePath)
+	if err != nil {
+		logger.Error("error opening state store", "error", err)
+		return
+	}
+	defer store.Close()
 
-	fmt.Println("Starting initial repository check...")
-	runCheck(ctx, config, client, configFile)
+	if *migrateConfigFlag {
+		if err := migrateConfig(configFile, logger, store); err != nil {
+			logger.Error("error migrating config", "error", err)
+			return
+		}
+		logger.Info("migration complete")
+		return
+	}
+
+	recorder := metrics.New()
+	client, rlTransport, err := createGithubClient(ctx, logger, *config, recorder)
+	if err != nil {
+		logger.Error("error creating github client", "error", err)
+		return
+	}
+
+	logger.Info("starting initial repository check")
+	runCheck(ctx, logger, config, client, store, recorder, rlTransport)
 
 	if config.Interval == 0 {
-		fmt.Println("Running in one-shot mode (no interval)")
+		logger.Info("running in one-shot mode")
 		return
 	}
 
@@ -61,17 +134,30 @@ func main() {
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
-	fmt.Printf("Running in daemon mode, checking every %v\n", interval)
+	listen := config.Metrics.Listen
+	if listen == "" {
+		listen = ":9090"
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := recorder.Serve(ctx, listen); err != nil {
+			logger.Error("metrics server stopped", "error", err)
+		}
+	}()
+	defer wg.Wait()
+
+	logger.Info("running in daemon mode", "interval", interval.String(), "metrics_listen", listen)
 
 	for {
 		select {
 		case <-ticker.C:
-			runCheck(ctx, config, client, configFile)
+			rlTransport.waitIfThrottled(ctx, logger)
+			runCheck(ctx, logger, config, client, store, recorder, rlTransport)
 		case <-sigChan:
-			fmt.Println("\nReceived shutdown signal, saving config and exiting...")
-			if err := saveConfig(configFile, config); err != nil {
-				_, _ = fmt.Fprintf(os.Stderr, "Error saving config: %v\n", err)
-			}
+			logger.Info("received shutdown signal, exiting")
 			return
 		case <-ctx.Done():
 			return
@@ -79,20 +165,17 @@ func main() {
 	}
 }
 
-func runCheck(ctx context.Context, config *Config, client *github.Client, configFile string) {
-	fmt.Printf("[%s] Checking %d repositories...\n", time.Now().Format(time.RFC3339), len(config.Repositories))
+func runCheck(ctx context.Context, logger *slog.Logger, config *Config, client *githubv4.Client, store state.Store, recorder *metrics.Recorder, rlTransport *rateLimitTransport) {
+	start := time.Now()
+	logger.Info("checking repositories", "repo_count", len(config.Repositories))
 
-	err := checkRepositories(ctx, config, client)
-	if err != nil {
-		_, _ = fmt.Fprintf(os.Stderr, "Error checking repositories: %v\n", err)
+	if err := checkRepositories(ctx, logger, config, client, store, recorder, rlTransport); err != nil {
+		logger.Error("error checking repositories", "error", err)
 	}
 
-	err = saveConfig(configFile, config)
-	if err != nil {
-		_, _ = fmt.Fprintf(os.Stderr, "Error writing config: %v\n", err)
-	}
-
-	fmt.Println("Check completed")
+	duration := time.Since(start)
+	recorder.ObserveCheckDuration(duration)
+	logger.Info("check completed", "duration_ms", duration.Milliseconds())
 }
 
 func getConfigFile() string {
@@ -115,33 +198,70 @@ func loadConfig(filename string) (*Config, error) {
 	return &config, nil
 }
 
-func saveConfig(filename string, config *Config) error {
-	data, err := yaml.Marshal(config)
-	if err != nil {
-		return err
-	}
-	return os.WriteFile(filename, data, 0644)
-}
-
-func createGithubClient(ctx context.Context, config Config) *github.Client {
-	accessToken := config.AccessToken
-	if accessToken == "" {
-		accessToken = os.Getenv("GITHUB_TOKEN")
+// createGithubClient builds the GraphQL client used to talk to the GitHub
+// API, wrapping its transport in a rateLimitTransport so the daemon loop can
+// see the rate limit headers GitHub returns on every response.
+//
+// Authentication is, in order of preference: a GitHub App installation
+// (config.GitHubApp), a personal access token (config.AccessToken or
+// GITHUB_TOKEN), or an unauthenticated client. When config.EnterpriseURL is
+// set, the client talks to that GitHub Enterprise Server instance instead of
+// github.com.
+func createGithubClient(ctx context.Context, logger *slog.Logger, config Config, recorder *metrics.Recorder) (*githubv4.Client, *rateLimitTransport, error) {
+	var transport http.RoundTripper = http.DefaultTransport
+
+	switch {
+	case config.GitHubApp.enabled():
+		logger.Debug("using GitHub App installation for authentication", "app_id", config.GitHubApp.AppID, "installation_id", config.GitHubApp.InstallationID)
+		appTransport, err := ghinstallation.NewKeyFromFile(transport, config.GitHubApp.AppID, config.GitHubApp.InstallationID, config.GitHubApp.PrivateKeyPath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error configuring GitHub App authentication: %w", err)
+		}
+		if config.EnterpriseURL != "" {
+			appTransport.BaseURL = restBaseURL(config.EnterpriseURL)
+		}
+		transport = appTransport
+	default:
+		accessToken := config.AccessToken
+		if accessToken == "" {
+			accessToken = os.Getenv("GITHUB_TOKEN")
+			if accessToken != "" {
+				logger.Debug("using GitHub access token from environment variable")
+			}
+		}
 		if accessToken != "" {
-			fmt.Println("Using GitHub access token from environment variable")
+			if config.AccessToken != "" {
+				logger.Debug("using provided GitHub access token for authentication")
+			}
+			ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: accessToken})
+			transport = &oauth2.Transport{Base: transport, Source: ts}
 		}
 	}
-	if accessToken != "" {
-		if config.AccessToken != "" {
-			fmt.Println("Using provided GitHub access token for authentication")
-		}
-		ts := oauth2.StaticTokenSource(
-			&oauth2.Token{AccessToken: accessToken},
-		)
-		tc := oauth2.NewClient(ctx, ts)
-		return github.NewClient(tc)
+
+	rlTransport := newRateLimitTransport(transport, recorder)
+	httpClient := &http.Client{Transport: rlTransport}
+
+	if config.EnterpriseURL != "" {
+		logger.Debug("using GitHub Enterprise Server", "enterprise_url", config.EnterpriseURL)
+		return githubv4.NewEnterpriseClient(graphQLURL(config.EnterpriseURL), httpClient), rlTransport, nil
 	}
-	return github.NewClient(nil)
+	return githubv4.NewClient(httpClient), rlTransport, nil
+}
+
+// restBaseURL normalizes an enterprise_url (which may be given as the REST
+// v3 base, e.g. "https://ghe.company.com/api/v3/") into the form
+// ghinstallation expects: scheme, host, and path with no trailing slash.
+func restBaseURL(enterpriseURL string) string {
+	return strings.TrimRight(enterpriseURL, "/")
+}
+
+// graphQLURL derives a GitHub Enterprise Server's GraphQL endpoint from its
+// REST v3 base URL, e.g. "https://ghe.company.com/api/v3" becomes
+// "https://ghe.company.com/api/graphql".
+func graphQLURL(enterpriseURL string) string {
+	base := strings.TrimRight(enterpriseURL, "/")
+	base = strings.TrimSuffix(base, "/api/v3")
+	return base + "/api/graphql"
 }
 
 func parseSlug(slug string) (owner string, repo string, err error) {
@@ -152,104 +272,105 @@ func parseSlug(slug string) (owner string, repo string, err error) {
 	return parts[0], parts[1], nil
 }
 
-func checkRepositories(ctx context.Context, config *Config, client *github.Client) error {
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-
-	for i := range config.Repositories {
-		wg.Add(1)
-		go func(repo *Repository) {
-			defer wg.Done()
-
-			err := checkRepository(ctx, repo, client, config.Notifications)
-			if err != nil {
-				mu.Lock()
-				_, _ = fmt.Fprintf(os.Stderr, "Error checking repository %s: %v\n", repo.Slug, err)
-				mu.Unlock()
-			}
-		}(&config.Repositories[i])
+func checkRepositories(ctx context.Context, logger *slog.Logger, config *Config, client *githubv4.Client, store state.Store, recorder *metrics.Recorder, rlTransport *rateLimitTransport) error {
+	start := time.Now()
+	var releases [][]releaseNode
+	err := withRetry(ctx, defaultRetry, logger, "fetchLatestReleases", func() error {
+		batch, err := fetchLatestReleases(ctx, client, config.Repositories)
+		if err != nil {
+			return err
+		}
+		releases = batch
+		return nil
+	})
+	rateLimitRemaining, httpStatus := rlTransport.LastSeen()
+	if err != nil {
+		recorder.GithubAPIRequest("error")
+		return fmt.Errorf("error fetching releases: %w", err)
 	}
+	recorder.GithubAPIRequest("ok")
+	logger.Debug("fetched releases", "repo_count", len(config.Repositories), "duration_ms", time.Since(start).Milliseconds(), "http_status", httpStatus, "rate_limit_remaining", rateLimitRemaining)
+
+	now := time.Now()
+	forEachRepo(config.Repositories, config.Concurrency, func(i int, repo Repository) {
+		repoStart := time.Now()
+		recorder.SetLastCheckTimestamp(repo.Slug, now)
+
+		release, ok, err := selectRelease(repo, releases[i])
+		if err != nil {
+			logger.Error("error selecting release", "repo", repo.Slug, "duration_ms", time.Since(repoStart).Milliseconds(), "error", err)
+			return
+		}
+		if !ok {
+			logger.Debug("no matching release", "repo", repo.Slug, "duration_ms", time.Since(repoStart).Milliseconds())
+			return
+		}
+
+		if err := updateReleaseTag(ctx, logger, store, recorder, repo, release, config.Notifications, repoStart); err != nil {
+			logger.Error("error updating state", "repo", repo.Slug, "error", err)
+		}
+	})
 
-	wg.Wait()
 	return nil
 }
 
-func checkRepository(ctx context.Context, repo *Repository, client *github.Client, notifications []Notification) error {
-	owner, repoName, err := parseSlug(repo.Slug)
+func updateReleaseTag(ctx context.Context, logger *slog.Logger, store state.Store, recorder *metrics.Recorder, repo Repository, release releaseNode, notifications []Notification, repoStart time.Time) error {
+	tagName := string(release.TagName)
+
+	seenTag, _, err := store.GetSeenTag(repo.Slug)
 	if err != nil {
 		return err
 	}
-
-	tagName, err := getLatestReleaseTag(ctx, client, owner, repoName)
-	if err != nil {
-		return fmt.Errorf("error fetching release for %s: %w", repo.Slug, err)
+	if seenTag == tagName {
+		logger.Debug("release unchanged", "repo", repo.Slug, "tag", tagName, "duration_ms", time.Since(repoStart).Milliseconds())
+		return nil
 	}
 
-	updateReleaseTag(repo, tagName, notifications)
+	publishedAt := release.PublishedAt.Time
+	if err := store.RecordRelease(repo.Slug, tagName, publishedAt); err != nil {
+		return err
+	}
 
-	return nil
-}
+	logger.Info("new release detected", "repo", repo.Slug, "old_tag", seenTag, "new_tag", tagName, "duration_ms", time.Since(repoStart).Milliseconds())
+	recorder.ReleaseDetected(repo.Slug)
 
-func getLatestReleaseTag(ctx context.Context, client *github.Client, owner, repo string) (string, error) {
-	release, _, err := client.Repositories.GetLatestRelease(ctx, owner, repo)
-	if err != nil {
-		return "", err
+	event := notifier.ReleaseEvent{
+		Slug:        repo.Slug,
+		OldTag:      seenTag,
+		NewTag:      tagName,
+		PublishedAt: publishedAt,
+		Body:        string(release.Body),
+		HTMLURL:     string(release.URL),
 	}
-	return release.GetTagName(), nil
-}
+	notifyNewRelease(ctx, logger, recorder, event, notifications)
 
-func updateReleaseTag(repo *Repository, tagName string, notifications []Notification) {
-	if repo.CurrentReleaseTag != tagName {
-		repo.CurrentReleaseTag = tagName
-		notifyNewRelease(repo.Slug, tagName, notifications)
-	}
+	return nil
 }
 
-func notifyNewRelease(slug, tagName string, notifications []Notification) {
-	message := fmt.Sprintf("New release for %s: %s", slug, tagName)
-	fmt.Println(message)
-
-	for _, notification := range notifications {
-		formattedMessage := formatNotificationMessage(notification.RawURL, slug, tagName, message)
+func notifyNewRelease(ctx context.Context, logger *slog.Logger, recorder *metrics.Recorder, event notifier.ReleaseEvent, notifications []Notification) {
+	for _, n := range notifications {
+		cfg := notifier.Config{
+			Type:     n.Type,
+			URL:      n.RawURL,
+			Template: n.Template,
+			Filters:  n.Filters,
+		}
+		target := cfg.ResolvedType()
 
-		err := shoutrrr.Send(notification.RawURL, formattedMessage)
+		sender, err := notifier.New(cfg)
 		if err != nil {
-			_, _ = fmt.Fprintf(os.Stderr, "Error sending notification to %s: %v\n", notification.RawURL, err)
+			logger.Error("error building notifier", "url", n.RawURL, "error", err)
+			recorder.NotificationSent(target, "error")
+			continue
 		}
-	}
-}
 
-func formatNotificationMessage(url, slug, tagName, defaultMessage string) string {
-	if strings.HasPrefix(url, "generic+powerautomate") {
-		return formatTeamsPowerAutomateMessage(slug, tagName)
-	}
-	return defaultMessage
-}
-
-func formatTeamsPowerAutomateMessage(slug, tagName string) string {
-	return fmt.Sprintf(`{
-		"type": "message",
-		"attachments": [{
-			"contentType": "application/vnd.microsoft.card.adaptive",
-			"content": {
-				"type": "AdaptiveCard",
-				"version": "1.2",
-				"body": [{
-					"type": "TextBlock",
-					"text": "New Release Available",
-					"weight": "bolder",
-					"size": "large"
-				},{
-					"type": "FactSet",
-					"facts": [{
-						"title": "Repository:",
-						"value": "%s"
-					},{
-						"title": "Version:",
-						"value": "%s"
-					}]
-				}]
-			}
-		}]
-	}`, slug, tagName)
+		if err := sender.Send(ctx, event); err != nil {
+			logger.Error("error sending notification", "repo", event.Slug, "url", n.RawURL, "error", err)
+			recorder.NotificationSent(target, "error")
+			continue
+		}
+
+		logger.Debug("sent notification", "repo", event.Slug, "url", n.RawURL)
+		recorder.NotificationSent(target, "ok")
+	}
 }
@@ -0,0 +1,125 @@
+// Package metrics exposes Prometheus metrics and a /healthz endpoint for
+// running the monitor as a long-lived daemon.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Recorder collects metrics for a monitor run against its own Prometheus
+// registry, so it can't collide with anything else registered in-process.
+type Recorder struct {
+	registry *prometheus.Registry
+	server   *http.Server
+
+	releasesDetected      *prometheus.CounterVec
+	notificationSendTotal *prometheus.CounterVec
+	githubAPIRequests     *prometheus.CounterVec
+	rateLimitRemaining    prometheus.Gauge
+	lastCheckTimestamp    *prometheus.GaugeVec
+	checkDuration         prometheus.Histogram
+}
+
+// New builds a Recorder with all metrics registered.
+func New() *Recorder {
+	registry := prometheus.NewRegistry()
+	factory := promauto.With(registry)
+
+	return &Recorder{
+		registry: registry,
+		releasesDetected: factory.NewCounterVec(prometheus.CounterOpts{
+			Name: "releases_detected_total",
+			Help: "Number of new releases detected, per repository.",
+		}, []string{"repo"}),
+		notificationSendTotal: factory.NewCounterVec(prometheus.CounterOpts{
+			Name: "notification_send_total",
+			Help: "Number of notification sends, per target type and status.",
+		}, []string{"target", "status"}),
+		githubAPIRequests: factory.NewCounterVec(prometheus.CounterOpts{
+			Name: "github_api_requests_total",
+			Help: "Number of GitHub API requests, per outcome status.",
+		}, []string{"status"}),
+		rateLimitRemaining: factory.NewGauge(prometheus.GaugeOpts{
+			Name: "github_rate_limit_remaining",
+			Help: "Remaining GitHub API rate limit as of the last response.",
+		}),
+		lastCheckTimestamp: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "last_check_timestamp_seconds",
+			Help: "Unix timestamp of the last check for a repository.",
+		}, []string{"repo"}),
+		checkDuration: factory.NewHistogram(prometheus.HistogramOpts{
+			Name:    "check_duration_seconds",
+			Help:    "Duration of a full repository check cycle.",
+			Buckets: prometheus.DefBuckets,
+		}),
+	}
+}
+
+// ReleaseDetected records a newly observed release for repo.
+func (r *Recorder) ReleaseDetected(repo string) {
+	r.releasesDetected.WithLabelValues(repo).Inc()
+}
+
+// NotificationSent records the outcome ("ok" or "error") of sending a
+// notification of the given target type (e.g. "slack", "shoutrrr").
+func (r *Recorder) NotificationSent(target, status string) {
+	r.notificationSendTotal.WithLabelValues(target, status).Inc()
+}
+
+// GithubAPIRequest records the outcome ("ok" or "error") of a GitHub API
+// request.
+func (r *Recorder) GithubAPIRequest(status string) {
+	r.githubAPIRequests.WithLabelValues(status).Inc()
+}
+
+// SetRateLimitRemaining updates the last-known GitHub API rate limit.
+func (r *Recorder) SetRateLimitRemaining(remaining float64) {
+	r.rateLimitRemaining.Set(remaining)
+}
+
+// SetLastCheckTimestamp records when repo was last checked.
+func (r *Recorder) SetLastCheckTimestamp(repo string, t time.Time) {
+	r.lastCheckTimestamp.WithLabelValues(repo).Set(float64(t.Unix()))
+}
+
+// ObserveCheckDuration records how long a full check cycle took.
+func (r *Recorder) ObserveCheckDuration(d time.Duration) {
+	r.checkDuration.Observe(d.Seconds())
+}
+
+// Serve starts an HTTP server on listen exposing /metrics and /healthz. It
+// blocks until ctx is done, at which point it shuts down gracefully.
+func (r *Recorder) Serve(ctx context.Context, listen string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(r.registry, promhttp.HandlerOpts{}))
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	})
+
+	r.server = &http.Server{Addr: listen, Handler: mux}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- r.server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return r.server.Shutdown(shutdownCtx)
+	}
+}
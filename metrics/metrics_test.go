@@ -0,0 +1,133 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestRecorderReleaseDetected(t *testing.T) {
+	r := New()
+	r.ReleaseDetected("owner/repo")
+	r.ReleaseDetected("owner/repo")
+
+	got := testutil.ToFloat64(r.releasesDetected.WithLabelValues("owner/repo"))
+	if got != 2 {
+		t.Errorf("releases_detected_total{repo=owner/repo} = %v, want 2", got)
+	}
+}
+
+func TestRecorderNotificationSent(t *testing.T) {
+	r := New()
+	r.NotificationSent("slack", "ok")
+	r.NotificationSent("slack", "error")
+
+	if got := testutil.ToFloat64(r.notificationSendTotal.WithLabelValues("slack", "ok")); got != 1 {
+		t.Errorf("notification_send_total{slack,ok} = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(r.notificationSendTotal.WithLabelValues("slack", "error")); got != 1 {
+		t.Errorf("notification_send_total{slack,error} = %v, want 1", got)
+	}
+}
+
+func TestRecorderGithubAPIRequest(t *testing.T) {
+	r := New()
+	r.GithubAPIRequest("ok")
+
+	if got := testutil.ToFloat64(r.githubAPIRequests.WithLabelValues("ok")); got != 1 {
+		t.Errorf("github_api_requests_total{ok} = %v, want 1", got)
+	}
+}
+
+func TestRecorderSetRateLimitRemaining(t *testing.T) {
+	r := New()
+	r.SetRateLimitRemaining(4999)
+
+	if got := testutil.ToFloat64(r.rateLimitRemaining); got != 4999 {
+		t.Errorf("github_rate_limit_remaining = %v, want 4999", got)
+	}
+}
+
+func TestRecorderSetLastCheckTimestamp(t *testing.T) {
+	r := New()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	r.SetLastCheckTimestamp("owner/repo", now)
+
+	got := testutil.ToFloat64(r.lastCheckTimestamp.WithLabelValues("owner/repo"))
+	if got != float64(now.Unix()) {
+		t.Errorf("last_check_timestamp_seconds{repo=owner/repo} = %v, want %v", got, now.Unix())
+	}
+}
+
+func TestRecorderObserveCheckDuration(t *testing.T) {
+	r := New()
+	r.ObserveCheckDuration(2 * time.Second)
+
+	families, err := r.registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather returned error: %v", err)
+	}
+	var got uint64
+	for _, mf := range families {
+		if mf.GetName() == "check_duration_seconds" {
+			got = mf.GetMetric()[0].GetHistogram().GetSampleCount()
+		}
+	}
+	if got != 1 {
+		t.Errorf("check_duration_seconds observation count = %d, want 1", got)
+	}
+}
+
+func TestRecorderServeExposesMetricsAndHealthz(t *testing.T) {
+	r := New()
+	r.ReleaseDetected("owner/repo")
+
+	ln := httptest.NewServer(nil)
+	addr := ln.Listener.Addr().String()
+	ln.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- r.Serve(ctx, addr) }()
+
+	deadline := time.Now().Add(2 * time.Second)
+	var resp *http.Response
+	var err error
+	for time.Now().Before(deadline) {
+		resp, err = http.Get("http://" + addr + "/healthz")
+		if err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("GET /healthz: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("/healthz status = %d, want 200", resp.StatusCode)
+	}
+
+	metricsResp, err := http.Get("http://" + addr + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics: %v", err)
+	}
+	defer metricsResp.Body.Close()
+
+	buf := make([]byte, 64*1024)
+	n, _ := metricsResp.Body.Read(buf)
+	body := string(buf[:n])
+	if !strings.Contains(body, "releases_detected_total") {
+		t.Errorf("/metrics response missing releases_detected_total, got: %s", body)
+	}
+
+	cancel()
+	if err := <-errCh; err != nil {
+		t.Errorf("Serve returned error after shutdown: %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/wimwenigerkind/github-release-monitor/state"
+)
+
+// legacyConfig reads just enough of a pre-state-store config.yml to recover
+// the current_release_tag values that saveConfig used to write back.
+type legacyConfig struct {
+	Repositories []struct {
+		Slug              string `yaml:"slug"`
+		CurrentReleaseTag string `yaml:"current_release_tag"`
+	} `yaml:"repositories"`
+}
+
+// migrateConfig copies current_release_tag values out of configFile and
+// into store, for users upgrading from before the state store existed.
+// The original publish time isn't known, so entries are recorded with a
+// zero PublishedAt; this only affects display, not tag comparisons.
+func migrateConfig(configFile string, logger *slog.Logger, store state.Store) error {
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		return err
+	}
+
+	var legacy legacyConfig
+	if err := yaml.Unmarshal(data, &legacy); err != nil {
+		return err
+	}
+
+	for _, repo := range legacy.Repositories {
+		if repo.CurrentReleaseTag == "" {
+			continue
+		}
+		if err := store.RecordRelease(repo.Slug, repo.CurrentReleaseTag, time.Time{}); err != nil {
+			return fmt.Errorf("error migrating %s: %w", repo.Slug, err)
+		}
+		logger.Info("migrated repository", "repo", repo.Slug, "tag", repo.CurrentReleaseTag)
+	}
+
+	return nil
+}
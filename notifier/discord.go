@@ -0,0 +1,45 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+const defaultDiscordTemplate = "**New release for {{.Slug}}**: `{{.NewTag}}`\n\n{{.Notes}}"
+
+// discordNotifier posts a message to a Discord webhook as a plain content
+// field; Discord renders its own limited markdown from that.
+type discordNotifier struct {
+	url      string
+	template string
+	filters  *Filters
+	client   *http.Client
+}
+
+func newDiscord(cfg Config) Notifier {
+	return &discordNotifier{url: cfg.URL, template: cfg.Template, filters: cfg.Filters, client: http.DefaultClient}
+}
+
+func (n *discordNotifier) Send(ctx context.Context, event ReleaseEvent) error {
+	ok, err := n.filters.Match(event.NewTag)
+	if err != nil || !ok {
+		return err
+	}
+
+	// Discord's markdown dialect already matches GitHub's closely enough
+	// (bold, links, bullets) that the body needs no per-channel rewriting.
+	data := templateData{ReleaseEvent: event, Notes: formatNotes(event.Body, func(s string) string { return s })}
+	content, err := renderTemplate(n.template, defaultDiscordTemplate, data)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(map[string]string{"content": content})
+	if err != nil {
+		return fmt.Errorf("error marshaling Discord payload: %w", err)
+	}
+
+	return postJSON(ctx, n.client, n.url, payload)
+}
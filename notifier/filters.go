@@ -0,0 +1,59 @@
+package notifier
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// Filters narrows which releases a notification entry fires for.
+type Filters struct {
+	TagRegex   string `yaml:"tag_regex,omitempty"`
+	MinVersion string `yaml:"min_version,omitempty"`
+	MaxVersion string `yaml:"max_version,omitempty"`
+}
+
+// Match reports whether tag passes all configured filters. A nil *Filters
+// always matches. MinVersion/MaxVersion are only enforced when tag is a
+// valid semantic version; they're silently skipped for tags that aren't
+// (e.g. "nightly"), since tag_regex is the right tool for those.
+func (f *Filters) Match(tag string) (bool, error) {
+	if f == nil {
+		return true, nil
+	}
+
+	if f.TagRegex != "" {
+		re, err := regexp.Compile(f.TagRegex)
+		if err != nil {
+			return false, fmt.Errorf("invalid tag_regex: %w", err)
+		}
+		if !re.MatchString(tag) {
+			return false, nil
+		}
+	}
+
+	version := normalizeVersion(tag)
+	if !semver.IsValid(version) {
+		return true, nil
+	}
+
+	if f.MinVersion != "" && semver.Compare(version, normalizeVersion(f.MinVersion)) < 0 {
+		return false, nil
+	}
+	if f.MaxVersion != "" && semver.Compare(version, normalizeVersion(f.MaxVersion)) > 0 {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// normalizeVersion prefixes v with "v" if missing, as required by
+// golang.org/x/mod/semver.
+func normalizeVersion(v string) string {
+	if strings.HasPrefix(v, "v") {
+		return v
+	}
+	return "v" + v
+}
@@ -0,0 +1,74 @@
+package notifier
+
+import "testing"
+
+func TestFiltersMatchNilAlwaysMatches(t *testing.T) {
+	var f *Filters
+	ok, err := f.Match("v1.0.0")
+	if err != nil || !ok {
+		t.Errorf("Match() = %v, %v, want true, nil", ok, err)
+	}
+}
+
+func TestFiltersMatchTagRegex(t *testing.T) {
+	f := &Filters{TagRegex: `^v\d+\.\d+\.\d+$`}
+
+	if ok, err := f.Match("v1.2.3"); err != nil || !ok {
+		t.Errorf("Match(v1.2.3) = %v, %v, want true, nil", ok, err)
+	}
+	if ok, err := f.Match("v1.2.3-rc1"); err != nil || ok {
+		t.Errorf("Match(v1.2.3-rc1) = %v, %v, want false, nil", ok, err)
+	}
+}
+
+func TestFiltersMatchInvalidTagRegex(t *testing.T) {
+	f := &Filters{TagRegex: "("}
+
+	if _, err := f.Match("v1.0.0"); err == nil {
+		t.Fatal("expected an error for an invalid tag_regex")
+	}
+}
+
+func TestFiltersMatchMinMaxVersion(t *testing.T) {
+	f := &Filters{MinVersion: "1.0.0", MaxVersion: "2.0.0"}
+
+	cases := []struct {
+		tag  string
+		want bool
+	}{
+		{"v0.9.0", false},
+		{"v1.0.0", true},
+		{"v1.5.0", true},
+		{"v2.0.0", true},
+		{"v2.0.1", false},
+	}
+
+	for _, c := range cases {
+		ok, err := f.Match(c.tag)
+		if err != nil {
+			t.Errorf("Match(%s) returned error: %v", c.tag, err)
+			continue
+		}
+		if ok != c.want {
+			t.Errorf("Match(%s) = %v, want %v", c.tag, ok, c.want)
+		}
+	}
+}
+
+func TestFiltersMatchNonSemverTagSkipsVersionBounds(t *testing.T) {
+	f := &Filters{MinVersion: "1.0.0", MaxVersion: "2.0.0"}
+
+	ok, err := f.Match("nightly")
+	if err != nil || !ok {
+		t.Errorf("Match(nightly) = %v, %v, want true, nil", ok, err)
+	}
+}
+
+func TestFiltersMatchVersionWithoutVPrefix(t *testing.T) {
+	f := &Filters{MinVersion: "1.0.0"}
+
+	ok, err := f.Match("1.5.0")
+	if err != nil || !ok {
+		t.Errorf("Match(1.5.0) = %v, %v, want true, nil", ok, err)
+	}
+}
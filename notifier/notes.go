@@ -0,0 +1,146 @@
+package notifier
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	headingRe      = regexp.MustCompile(`(?m)^#{1,6}\s+`)
+	boldRe         = regexp.MustCompile(`\*\*(.+?)\*\*`)
+	linkRe         = regexp.MustCompile(`\[([^\]]+)\]\(([^)]+)\)`)
+	bulletRe       = regexp.MustCompile(`(?m)^\s*[-*]\s+`)
+	conventionalRe = regexp.MustCompile(`(?i)^(feat|fix|chore|docs|refactor|perf|test|build|ci|style|revert)(\([^)]*\))?!?:\s*(.+)$`)
+)
+
+// renderNotesPlain strips common markdown syntax from a GitHub release body
+// so it reads sensibly as plain text (Shoutrrr, generic webhook).
+func renderNotesPlain(body string) string {
+	text := headingRe.ReplaceAllString(body, "")
+	text = boldRe.ReplaceAllString(text, "$1")
+	text = linkRe.ReplaceAllString(text, "$1 ($2)")
+	text = bulletRe.ReplaceAllString(text, "- ")
+	return strings.TrimSpace(text)
+}
+
+// renderNotesSlack converts a GitHub release body into Slack mrkdwn:
+// headings are dropped, "**bold**" becomes "*bold*" and
+// "[text](url)" becomes "<url|text>".
+func renderNotesSlack(body string) string {
+	text := headingRe.ReplaceAllString(body, "")
+	text = boldRe.ReplaceAllString(text, "*$1*")
+	text = linkRe.ReplaceAllString(text, "<$2|$1>")
+	return strings.TrimSpace(text)
+}
+
+// commitSection groups release-body lines that share a conventional-commit
+// prefix, e.g. all "feat:" lines.
+type commitSection struct {
+	Prefix   string
+	Messages []string
+}
+
+// summarizeCommits groups conventional-commit-style lines ("feat: ...",
+// "fix(scope): ...") found in body by their prefix, preserving the order
+// prefixes first appear in. Lines that don't match the convention are
+// grouped under "other".
+func summarizeCommits(body string) []commitSection {
+	var order []string
+	groups := map[string][]string{}
+
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(bulletRe.ReplaceAllString(line, ""))
+		if line == "" {
+			continue
+		}
+
+		prefix := "other"
+		message := line
+		if m := conventionalRe.FindStringSubmatch(line); m != nil {
+			prefix = strings.ToLower(m[1])
+			message = m[3]
+		}
+
+		if _, ok := groups[prefix]; !ok {
+			order = append(order, prefix)
+		}
+		groups[prefix] = append(groups[prefix], message)
+	}
+
+	sections := make([]commitSection, 0, len(order))
+	for _, prefix := range order {
+		sections = append(sections, commitSection{Prefix: prefix, Messages: groups[prefix]})
+	}
+	return sections
+}
+
+// isSectionTitle reports whether line is one of the heading lines formatNotes
+// inserts ahead of a grouped conventional-commit section (e.g. "Features"),
+// as opposed to a bullet or plain note line.
+func isSectionTitle(line string) bool {
+	for _, title := range sectionTitles {
+		if line == title {
+			return true
+		}
+	}
+	return false
+}
+
+var sectionTitles = map[string]string{
+	"feat":     "Features",
+	"fix":      "Bug Fixes",
+	"chore":    "Chores",
+	"docs":     "Documentation",
+	"refactor": "Refactors",
+	"perf":     "Performance",
+	"test":     "Tests",
+	"build":    "Build",
+	"ci":       "CI",
+	"style":    "Style",
+	"revert":   "Reverts",
+	"other":    "Other Changes",
+}
+
+// formatNotes renders a GitHub release body into notes ready for a
+// notification message. When at least one line of the body is a
+// conventional-commit line - as in GitHub's auto-generated "What's Changed"
+// notes - the whole body is grouped into per-type sections first, similar
+// to a changelog generator, with every other line under "Other Changes";
+// otherwise the body is rendered as-is.
+// An empty body renders as a short placeholder.
+func formatNotes(body string, render func(string) string) string {
+	trimmed := strings.TrimSpace(body)
+	if trimmed == "" {
+		return "No release notes provided."
+	}
+
+	sections := summarizeCommits(trimmed)
+	conventional := 0
+	for _, s := range sections {
+		if s.Prefix != "other" {
+			conventional++
+		}
+	}
+	if conventional == 0 {
+		return render(trimmed)
+	}
+
+	var b strings.Builder
+	for i, s := range sections {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		title, ok := sectionTitles[s.Prefix]
+		if !ok {
+			title = s.Prefix
+		}
+		b.WriteString(title)
+		b.WriteString("\n")
+		for _, m := range s.Messages {
+			b.WriteString("- ")
+			b.WriteString(m)
+			b.WriteString("\n")
+		}
+	}
+	return render(strings.TrimRight(b.String(), "\n"))
+}
@@ -0,0 +1,79 @@
+package notifier
+
+import "testing"
+
+func TestFormatNotesEmptyBody(t *testing.T) {
+	got := formatNotes("", renderNotesPlain)
+	want := "No release notes provided."
+	if got != want {
+		t.Errorf("formatNotes(\"\") = %q, want %q", got, want)
+	}
+
+	got = formatNotes("   \n  ", renderNotesPlain)
+	if got != want {
+		t.Errorf("formatNotes(whitespace) = %q, want %q", got, want)
+	}
+}
+
+func TestFormatNotesGroupsConventionalCommits(t *testing.T) {
+	body := "- feat: add widget\n- fix(api): stop crashing\n- feat: add gadget"
+	got := formatNotes(body, renderNotesPlain)
+	want := "Features\n- add widget\n- add gadget\n\nBug Fixes\n- stop crashing"
+	if got != want {
+		t.Errorf("formatNotes grouped output mismatch\ngot:  %q\nwant: %q", got, want)
+	}
+}
+
+func TestFormatNotesLeavesNonConventionalBodyAsIs(t *testing.T) {
+	body := "## What's new\nJust some prose about this release."
+	got := formatNotes(body, renderNotesPlain)
+	want := "What's new\nJust some prose about this release."
+	if got != want {
+		t.Errorf("formatNotes(%q) = %q, want %q", body, got, want)
+	}
+}
+
+func TestFormatNotesAppliesRenderFunc(t *testing.T) {
+	body := "**bold** [link](https://example.com)"
+	got := formatNotes(body, renderNotesSlack)
+	want := "*bold* <https://example.com|link>"
+	if got != want {
+		t.Errorf("formatNotes with renderNotesSlack = %q, want %q", got, want)
+	}
+}
+
+func TestSummarizeCommitsGroupsByPrefixInFirstSeenOrder(t *testing.T) {
+	body := "fix: a\nfeat: b\nfix: c\nsomething else"
+	sections := summarizeCommits(body)
+
+	if len(sections) != 3 {
+		t.Fatalf("expected 3 sections, got %d: %+v", len(sections), sections)
+	}
+	if sections[0].Prefix != "fix" || len(sections[0].Messages) != 2 {
+		t.Errorf("expected fix section first with 2 messages, got %+v", sections[0])
+	}
+	if sections[1].Prefix != "feat" {
+		t.Errorf("expected feat section second, got %+v", sections[1])
+	}
+	if sections[2].Prefix != "other" || sections[2].Messages[0] != "something else" {
+		t.Errorf("expected trailing other section, got %+v", sections[2])
+	}
+}
+
+func TestRenderNotesPlainStripsMarkdown(t *testing.T) {
+	body := "## Heading\n**bold** and [a link](https://example.com)\n- bullet"
+	got := renderNotesPlain(body)
+	want := "Heading\nbold and a link (https://example.com)\n- bullet"
+	if got != want {
+		t.Errorf("renderNotesPlain(%q) = %q, want %q", body, got, want)
+	}
+}
+
+func TestIsSectionTitle(t *testing.T) {
+	if !isSectionTitle("Features") {
+		t.Error("expected \"Features\" to be a section title")
+	}
+	if isSectionTitle("- add widget") {
+		t.Error("expected a bullet line not to be a section title")
+	}
+}
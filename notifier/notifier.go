@@ -0,0 +1,79 @@
+// Package notifier delivers release events to notification destinations
+// (Shoutrrr services, Slack, Teams, Discord, or a generic webhook), each
+// configurable with its own message template and filters.
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ReleaseEvent describes a single newly observed release. It is the data
+// made available to notification templates via
+// {{.Slug}} {{.OldTag}} {{.NewTag}} {{.PublishedAt}} {{.Body}} {{.HTMLURL}}.
+type ReleaseEvent struct {
+	Slug        string
+	OldTag      string
+	NewTag      string
+	PublishedAt time.Time
+	Body        string
+	HTMLURL     string
+}
+
+// Notifier delivers a ReleaseEvent to a single destination.
+type Notifier interface {
+	Send(ctx context.Context, event ReleaseEvent) error
+}
+
+// Config is the subset of a config.yml `notifications:` entry needed to
+// build a Notifier.
+type Config struct {
+	Type     string
+	URL      string
+	Template string
+	Filters  *Filters
+}
+
+// ResolvedType returns cfg.Type, or the type InferType derives from cfg.URL
+// when Type is empty. Callers that need to know which notifier New will
+// build for cfg (e.g. to label a metric) should use this instead of reading
+// cfg.Type directly.
+func (cfg Config) ResolvedType() string {
+	if cfg.Type != "" {
+		return cfg.Type
+	}
+	return InferType(cfg.URL)
+}
+
+// New builds the Notifier described by cfg. Type defaults to "shoutrrr",
+// except for Teams PowerAutomate URLs, which previously relied on a
+// hardcoded prefix check and now map to "teams" for compatibility with
+// existing config.yml files that don't set type explicitly.
+func New(cfg Config) (Notifier, error) {
+	typ := cfg.ResolvedType()
+	switch typ {
+	case "shoutrrr":
+		return newShoutrrr(cfg), nil
+	case "slack":
+		return newSlack(cfg), nil
+	case "teams":
+		return newTeams(cfg), nil
+	case "discord":
+		return newDiscord(cfg), nil
+	case "webhook":
+		return newWebhook(cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown notifier type %q", typ)
+	}
+}
+
+// InferType guesses a notifier type from url when a config.yml entry
+// doesn't set `type` explicitly.
+func InferType(url string) string {
+	if strings.HasPrefix(url, "generic+powerautomate") {
+		return "teams"
+	}
+	return "shoutrrr"
+}
@@ -0,0 +1,36 @@
+package notifier
+
+import (
+	"context"
+
+	"github.com/containrrr/shoutrrr"
+)
+
+const defaultShoutrrrTemplate = "New release for {{.Slug}}: {{.NewTag}}\n\n{{.Notes}}"
+
+// shoutrrrNotifier sends plain-text messages through any Shoutrrr-supported
+// service URL (see https://containrrr.dev/shoutrrr/services/overview/).
+type shoutrrrNotifier struct {
+	url      string
+	template string
+	filters  *Filters
+}
+
+func newShoutrrr(cfg Config) Notifier {
+	return &shoutrrrNotifier{url: cfg.URL, template: cfg.Template, filters: cfg.Filters}
+}
+
+func (n *shoutrrrNotifier) Send(ctx context.Context, event ReleaseEvent) error {
+	ok, err := n.filters.Match(event.NewTag)
+	if err != nil || !ok {
+		return err
+	}
+
+	data := templateData{ReleaseEvent: event, Notes: formatNotes(event.Body, renderNotesPlain)}
+	message, err := renderTemplate(n.template, defaultShoutrrrTemplate, data)
+	if err != nil {
+		return err
+	}
+
+	return shoutrrr.Send(n.url, message)
+}
@@ -0,0 +1,42 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+const defaultSlackTemplate = "New release for *{{.Slug}}*: `{{.NewTag}}`\n\n{{.Notes}}"
+
+// slackNotifier posts mrkdwn-formatted text to a Slack incoming webhook.
+type slackNotifier struct {
+	url      string
+	template string
+	filters  *Filters
+	client   *http.Client
+}
+
+func newSlack(cfg Config) Notifier {
+	return &slackNotifier{url: cfg.URL, template: cfg.Template, filters: cfg.Filters, client: http.DefaultClient}
+}
+
+func (n *slackNotifier) Send(ctx context.Context, event ReleaseEvent) error {
+	ok, err := n.filters.Match(event.NewTag)
+	if err != nil || !ok {
+		return err
+	}
+
+	data := templateData{ReleaseEvent: event, Notes: formatNotes(event.Body, renderNotesSlack)}
+	text, err := renderTemplate(n.template, defaultSlackTemplate, data)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return fmt.Errorf("error marshaling Slack payload: %w", err)
+	}
+
+	return postJSON(ctx, n.client, n.url, payload)
+}
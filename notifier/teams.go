@@ -0,0 +1,147 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// defaultTeamsTemplate renders the Adaptive Card body that used to be
+// hardcoded in formatTeamsPowerAutomateMessage. Send never renders it: a
+// custom template, when set, is rendered instead of this fallback, and
+// without one the card is built in Go via buildTeamsCard so release notes
+// can be appended as wrapped TextBlocks.
+const defaultTeamsTemplate = `{
+	"type": "message",
+	"attachments": [{
+		"contentType": "application/vnd.microsoft.card.adaptive",
+		"content": {
+			"type": "AdaptiveCard",
+			"version": "1.2",
+			"body": [{
+				"type": "TextBlock",
+				"text": "New Release Available",
+				"weight": "bolder",
+				"size": "large"
+			},{
+				"type": "FactSet",
+				"facts": [{
+					"title": "Repository:",
+					"value": "{{.Slug}}"
+				},{
+					"title": "Version:",
+					"value": "{{.NewTag}}"
+				}]
+			}]
+		}
+	}]
+}`
+
+type adaptiveCard struct {
+	Type        string                   `json:"type"`
+	Attachments []adaptiveCardAttachment `json:"attachments"`
+}
+
+type adaptiveCardAttachment struct {
+	ContentType string              `json:"contentType"`
+	Content     adaptiveCardContent `json:"content"`
+}
+
+type adaptiveCardContent struct {
+	Type    string                `json:"type"`
+	Version string                `json:"version"`
+	Body    []adaptiveCardElement `json:"body"`
+}
+
+type adaptiveCardElement struct {
+	Type   string             `json:"type"`
+	Text   string             `json:"text,omitempty"`
+	Weight string             `json:"weight,omitempty"`
+	Size   string             `json:"size,omitempty"`
+	Wrap   bool               `json:"wrap,omitempty"`
+	Facts  []adaptiveCardFact `json:"facts,omitempty"`
+}
+
+type adaptiveCardFact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+// teamsNotifier posts an Adaptive Card to a Teams PowerAutomate webhook URL.
+type teamsNotifier struct {
+	url      string
+	template string
+	filters  *Filters
+	client   *http.Client
+}
+
+func newTeams(cfg Config) Notifier {
+	return &teamsNotifier{url: cfg.URL, template: cfg.Template, filters: cfg.Filters, client: http.DefaultClient}
+}
+
+func (n *teamsNotifier) Send(ctx context.Context, event ReleaseEvent) error {
+	ok, err := n.filters.Match(event.NewTag)
+	if err != nil || !ok {
+		return err
+	}
+
+	if n.template != "" {
+		data := templateData{ReleaseEvent: event, Notes: formatNotes(event.Body, renderNotesPlain)}
+		card, err := renderTemplate(n.template, defaultTeamsTemplate, data)
+		if err != nil {
+			return err
+		}
+		return postJSON(ctx, n.client, n.url, []byte(card))
+	}
+
+	card, err := json.Marshal(buildTeamsCard(event))
+	if err != nil {
+		return err
+	}
+
+	return postJSON(ctx, n.client, n.url, card)
+}
+
+// buildTeamsCard assembles an Adaptive Card with a FactSet for the
+// repository/version and one wrapped TextBlock per release-note line, so
+// the notes stay readable instead of overflowing a single block. Notes go
+// through formatNotes like every other channel, so conventional-commit
+// bodies get grouped into "Features"/"Bug Fixes"/... sections and an empty
+// body renders the same placeholder Shoutrrr/Slack/Discord/webhook do.
+func buildTeamsCard(event ReleaseEvent) adaptiveCard {
+	body := []adaptiveCardElement{
+		{Type: "TextBlock", Text: "New Release Available", Weight: "bolder", Size: "large"},
+		{Type: "FactSet", Facts: []adaptiveCardFact{
+			{Title: "Repository:", Value: event.Slug},
+			{Title: "Version:", Value: event.NewTag},
+		}},
+	}
+
+	notes := formatNotes(event.Body, renderNotesPlain)
+	for _, line := range strings.Split(notes, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			continue
+		case isSectionTitle(line):
+			body = append(body, adaptiveCardElement{Type: "TextBlock", Text: line, Weight: "bolder", Wrap: true})
+		case strings.HasPrefix(line, "- "):
+			body = append(body, adaptiveCardElement{Type: "TextBlock", Text: "• " + strings.TrimPrefix(line, "- "), Wrap: true})
+		default:
+			body = append(body, adaptiveCardElement{Type: "TextBlock", Text: line, Wrap: true})
+		}
+	}
+
+	return adaptiveCard{
+		Type: "message",
+		Attachments: []adaptiveCardAttachment{{
+			ContentType: "application/vnd.microsoft.card.adaptive",
+			Content: adaptiveCardContent{
+				Type:    "AdaptiveCard",
+				Version: "1.2",
+				Body:    body,
+			},
+		}},
+	}
+}
@@ -0,0 +1,48 @@
+package notifier
+
+import "testing"
+
+func TestBuildTeamsCardGroupsConventionalCommits(t *testing.T) {
+	event := ReleaseEvent{Slug: "o/r", NewTag: "v1.0.0", Body: "feat: add widget\nfix: stop crashing"}
+	card := buildTeamsCard(event)
+	blocks := card.Attachments[0].Content.Body
+
+	var texts []string
+	for _, b := range blocks {
+		texts = append(texts, b.Text)
+	}
+
+	want := []string{
+		"New Release Available",
+		"",
+		"Features",
+		"• add widget",
+		"Bug Fixes",
+		"• stop crashing",
+	}
+	if len(texts) != len(want) {
+		t.Fatalf("got %d blocks %v, want %d blocks %v", len(texts), texts, len(want), want)
+	}
+	for i, w := range want {
+		if w == "" {
+			continue // the FactSet block has no Text field
+		}
+		if texts[i] != w {
+			t.Errorf("block %d = %q, want %q", i, texts[i], w)
+		}
+	}
+
+	if blocks[2].Weight != "bolder" {
+		t.Errorf("expected the \"Features\" heading to be bold, got weight %q", blocks[2].Weight)
+	}
+}
+
+func TestBuildTeamsCardEmptyBodyRendersPlaceholder(t *testing.T) {
+	card := buildTeamsCard(ReleaseEvent{Slug: "o/r", NewTag: "v1.0.0"})
+	blocks := card.Attachments[0].Content.Body
+
+	last := blocks[len(blocks)-1]
+	if last.Text != "No release notes provided." {
+		t.Errorf("expected the empty-body placeholder, got %q", last.Text)
+	}
+}
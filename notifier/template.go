@@ -0,0 +1,35 @@
+package notifier
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+)
+
+// templateData is what notification templates execute against: every
+// ReleaseEvent field, plus Notes - the release body rendered for the
+// destination channel (see formatNotes).
+type templateData struct {
+	ReleaseEvent
+	Notes string
+}
+
+// renderTemplate renders tmplText against data, falling back to
+// defaultText when tmplText is empty.
+func renderTemplate(tmplText, defaultText string, data templateData) (string, error) {
+	if tmplText == "" {
+		tmplText = defaultText
+	}
+
+	tmpl, err := template.New("notification").Parse(tmplText)
+	if err != nil {
+		return "", fmt.Errorf("invalid template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("error rendering template: %w", err)
+	}
+
+	return buf.String(), nil
+}
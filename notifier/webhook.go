@@ -0,0 +1,39 @@
+package notifier
+
+import (
+	"context"
+	"net/http"
+)
+
+// defaultWebhookTemplate renders the ReleaseEvent as a generic JSON object
+// for destinations with no provider-specific format. Notes carries the
+// release body grouped into conventional-commit sections where applicable;
+// the raw markdown is still available as Body for consumers that want it.
+const defaultWebhookTemplate = `{"slug":{{.Slug | printf "%q"}},"old_tag":{{.OldTag | printf "%q"}},"new_tag":{{.NewTag | printf "%q"}},"published_at":{{.PublishedAt | printf "%q"}},"body":{{.Body | printf "%q"}},"notes":{{.Notes | printf "%q"}},"html_url":{{.HTMLURL | printf "%q"}}}`
+
+// webhookNotifier POSTs a JSON document to any HTTP endpoint.
+type webhookNotifier struct {
+	url      string
+	template string
+	filters  *Filters
+	client   *http.Client
+}
+
+func newWebhook(cfg Config) Notifier {
+	return &webhookNotifier{url: cfg.URL, template: cfg.Template, filters: cfg.Filters, client: http.DefaultClient}
+}
+
+func (n *webhookNotifier) Send(ctx context.Context, event ReleaseEvent) error {
+	ok, err := n.filters.Match(event.NewTag)
+	if err != nil || !ok {
+		return err
+	}
+
+	data := templateData{ReleaseEvent: event, Notes: formatNotes(event.Body, func(s string) string { return s })}
+	body, err := renderTemplate(n.template, defaultWebhookTemplate, data)
+	if err != nil {
+		return err
+	}
+
+	return postJSON(ctx, n.client, n.url, []byte(body))
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// defaultMaxHistory is how many releases are fetched per repository when
+// max_history is not set in config.yml.
+const defaultMaxHistory = 5
+
+// releaseNode mirrors the fields of GitHub's GraphQL `Release` object that
+// we need to pick the right "latest" release and, eventually, notify about it.
+type releaseNode struct {
+	TagName      githubv4.String
+	Name         githubv4.String
+	Body         githubv4.String
+	URL          githubv4.String `graphql:"url"`
+	IsPrerelease githubv4.Boolean
+	IsDraft      githubv4.Boolean
+	PublishedAt  githubv4.DateTime
+}
+
+// repositoryReleases is the shape queried for each aliased `repository`
+// field in fetchLatestReleases.
+type repositoryReleases struct {
+	Releases struct {
+		Nodes []releaseNode
+	} `graphql:"releases(first: $first, orderBy: {field: CREATED_AT, direction: DESC})"`
+}
+
+// fetchLatestReleases fetches up to defaultMaxHistory (or the largest
+// configured max_history) releases for every repository in repos using a
+// single GraphQL request, aliasing one `repository` field per repo. This
+// replaces the previous "one REST call per repo per tick" approach, which
+// burns through the rate limit quickly once dozens of repos are configured.
+//
+// The returned slice has one entry per repo, in the same order as repos,
+// containing that repo's releases ordered newest first.
+func fetchLatestReleases(ctx context.Context, client *githubv4.Client, repos []Repository) ([][]releaseNode, error) {
+	if len(repos) == 0 {
+		return nil, nil
+	}
+
+	maxHistory := defaultMaxHistory
+	for _, repo := range repos {
+		if repo.MaxHistory > maxHistory {
+			maxHistory = repo.MaxHistory
+		}
+	}
+
+	fields := make([]reflect.StructField, len(repos))
+	variables := map[string]interface{}{
+		"first": githubv4.Int(maxHistory),
+	}
+
+	for i, repo := range repos {
+		owner, name, err := parseSlug(repo.Slug)
+		if err != nil {
+			return nil, err
+		}
+
+		ownerVar := fmt.Sprintf("owner%d", i)
+		nameVar := fmt.Sprintf("name%d", i)
+		variables[ownerVar] = githubv4.String(owner)
+		variables[nameVar] = githubv4.String(name)
+
+		fields[i] = reflect.StructField{
+			Name: fmt.Sprintf("Repo%d", i),
+			Type: reflect.TypeOf(repositoryReleases{}),
+			Tag:  reflect.StructTag(fmt.Sprintf(`graphql:"repo%d: repository(owner: $%s, name: $%s)"`, i, ownerVar, nameVar)),
+		}
+	}
+
+	query := reflect.New(reflect.StructOf(fields))
+	if err := client.Query(ctx, query.Interface(), variables); err != nil {
+		return nil, fmt.Errorf("error querying releases: %w", err)
+	}
+
+	result := make([][]releaseNode, len(repos))
+	elem := query.Elem()
+	for i := range repos {
+		nodes := elem.Field(i).FieldByName("Releases").FieldByName("Nodes")
+		result[i] = nodes.Interface().([]releaseNode)
+	}
+
+	return result, nil
+}
+
+// selectRelease picks the newest release in releases (already ordered
+// newest first) that satisfies repo's include_prereleases, include_drafts
+// and tag_regex filters. ok is false if nothing matched.
+//
+// fetchLatestReleases fetches the largest max_history across all configured
+// repos in one batched query, so releases may hold more entries than this
+// repo asked for; it's bounded here to repo.MaxHistory (when set) so a
+// smaller window actually narrows the match, not just the fetch size.
+func selectRelease(repo Repository, releases []releaseNode) (release releaseNode, ok bool, err error) {
+	var tagPattern *regexp.Regexp
+	if repo.TagRegex != "" {
+		tagPattern, err = regexp.Compile(repo.TagRegex)
+		if err != nil {
+			return releaseNode{}, false, fmt.Errorf("invalid tag_regex for %s: %w", repo.Slug, err)
+		}
+	}
+
+	if repo.MaxHistory > 0 && repo.MaxHistory < len(releases) {
+		releases = releases[:repo.MaxHistory]
+	}
+
+	for _, candidate := range releases {
+		if bool(candidate.IsPrerelease) && !repo.IncludePrereleases {
+			continue
+		}
+		if bool(candidate.IsDraft) && !repo.IncludeDrafts {
+			continue
+		}
+		if tagPattern != nil && !tagPattern.MatchString(string(candidate.TagName)) {
+			continue
+		}
+		return candidate, true, nil
+	}
+
+	return releaseNode{}, false, nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import "testing"
+
+func TestSelectReleaseRespectsMaxHistory(t *testing.T) {
+	releases := []releaseNode{
+		{TagName: "v2.0.0", IsDraft: true},
+		{TagName: "v1.9.0"},
+		{TagName: "v1.8.0"},
+	}
+
+	repo := Repository{Slug: "owner/repo", MaxHistory: 1}
+
+	_, ok, err := selectRelease(repo, releases)
+	if err != nil {
+		t.Fatalf("selectRelease returned error: %v", err)
+	}
+	if ok {
+		t.Fatalf("expected no match within the first release (a draft), but got one")
+	}
+}
+
+func TestSelectReleaseMatchesWithinMaxHistory(t *testing.T) {
+	releases := []releaseNode{
+		{TagName: "v2.0.0", IsDraft: true},
+		{TagName: "v1.9.0"},
+	}
+
+	repo := Repository{Slug: "owner/repo", MaxHistory: 2}
+
+	release, ok, err := selectRelease(repo, releases)
+	if err != nil {
+		t.Fatalf("selectRelease returned error: %v", err)
+	}
+	if !ok {
+		t.Fatal("expected a match within the first two releases")
+	}
+	if string(release.TagName) != "v1.9.0" {
+		t.Errorf("expected v1.9.0, got %s", release.TagName)
+	}
+}
+
+func TestSelectReleaseZeroMaxHistoryUsesFullWindow(t *testing.T) {
+	releases := []releaseNode{
+		{TagName: "v2.0.0", IsDraft: true},
+		{TagName: "v1.9.0"},
+	}
+
+	repo := Repository{Slug: "owner/repo"}
+
+	release, ok, err := selectRelease(repo, releases)
+	if err != nil {
+		t.Fatalf("selectRelease returned error: %v", err)
+	}
+	if !ok {
+		t.Fatal("expected a match")
+	}
+	if string(release.TagName) != "v1.9.0" {
+		t.Errorf("expected v1.9.0, got %s", release.TagName)
+	}
+}
+
+func TestSelectReleaseFiltersPrereleasesDraftsAndTagRegex(t *testing.T) {
+	releases := []releaseNode{
+		{TagName: "v2.0.0-rc1", IsPrerelease: true},
+		{TagName: "v1.9.0-draft", IsDraft: true},
+		{TagName: "other-tag"},
+		{TagName: "v1.8.0"},
+	}
+
+	repo := Repository{Slug: "owner/repo", TagRegex: `^v\d+\.\d+\.\d+$`}
+
+	release, ok, err := selectRelease(repo, releases)
+	if err != nil {
+		t.Fatalf("selectRelease returned error: %v", err)
+	}
+	if !ok {
+		t.Fatal("expected a match")
+	}
+	if string(release.TagName) != "v1.8.0" {
+		t.Errorf("expected v1.8.0, got %s", release.TagName)
+	}
+}
+
+func TestSelectReleaseInvalidTagRegex(t *testing.T) {
+	repo := Repository{Slug: "owner/repo", TagRegex: "("}
+
+	_, _, err := selectRelease(repo, []releaseNode{{TagName: "v1.0.0"}})
+	if err == nil {
+		t.Fatal("expected an error for an invalid tag_regex")
+	}
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wimwenigerkind/github-release-monitor/metrics"
+)
+
+// defaultConcurrency caps how many repositories are processed in parallel
+// when config.yml does not set concurrency.
+func defaultConcurrency() int {
+	if n := runtime.GOMAXPROCS(0); n < 8 {
+		return n
+	}
+	return 8
+}
+
+// rateLimitThreshold is the X-RateLimit-Remaining value below which the
+// daemon loop pauses the next check until the window resets, rather than
+// burning the rest of the budget and tripping a 403.
+const rateLimitThreshold = 10
+
+// retryConfig controls the jittered exponential backoff used to retry a
+// transient GitHub API failure.
+type retryConfig struct {
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+}
+
+// defaultRetry makes up to 5 attempts (4 retries), doubling the delay from
+// 1s up to a 60s cap.
+var defaultRetry = retryConfig{maxAttempts: 5, baseDelay: time.Second, maxDelay: 60 * time.Second}
+
+// withRetry calls fn until it succeeds, isRetryable reports its error as
+// permanent, or cfg.maxAttempts is reached, backing off with jittered
+// exponential delay between attempts.
+func withRetry(ctx context.Context, cfg retryConfig, logger *slog.Logger, label string, fn func() error) error {
+	delay := cfg.baseDelay
+
+	var err error
+	for attempt := 1; attempt <= cfg.maxAttempts; attempt++ {
+		if err = fn(); err == nil {
+			return nil
+		}
+		if !isRetryable(err) || attempt == cfg.maxAttempts {
+			return err
+		}
+
+		jittered := delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+		logger.Warn("retrying after transient github error", "target", label, "attempt", attempt, "delay", jittered.String(), "error", err)
+
+		select {
+		case <-time.After(jittered):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		delay *= 2
+		if delay > cfg.maxDelay {
+			delay = cfg.maxDelay
+		}
+	}
+	return err
+}
+
+// isRetryable reports whether err looks like a transient failure (secondary
+// rate limiting, abuse detection, or a 5xx) worth retrying, as opposed to a
+// permanent one like a bad slug or invalid token.
+func isRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, marker := range []string{"403", "429", "500", "502", "503", "504", "rate limit", "abuse"} {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// rateLimitTransport wraps an http.RoundTripper and records the
+// X-RateLimit-Remaining / X-RateLimit-Reset (or Retry-After) headers GitHub
+// returns on every response, so the daemon loop can pause before it trips
+// the rate limit instead of finding out from a 403.
+type rateLimitTransport struct {
+	base     http.RoundTripper
+	recorder *metrics.Recorder
+
+	mu         sync.Mutex
+	remaining  int
+	resetAt    time.Time
+	statusCode int
+}
+
+func newRateLimitTransport(base http.RoundTripper, recorder *metrics.Recorder) *rateLimitTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &rateLimitTransport{base: base, recorder: recorder, remaining: -1}
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	remaining, hasRemaining := parseIntHeader(resp.Header.Get("X-RateLimit-Remaining"))
+	resetUnix, hasReset := parseIntHeader(resp.Header.Get("X-RateLimit-Reset"))
+	retryAfter, hasRetryAfter := parseIntHeader(resp.Header.Get("Retry-After"))
+
+	t.mu.Lock()
+	t.statusCode = resp.StatusCode
+	if hasRemaining {
+		t.remaining = remaining
+		t.recorder.SetRateLimitRemaining(float64(remaining))
+	}
+	switch {
+	case hasRetryAfter:
+		t.resetAt = time.Now().Add(time.Duration(retryAfter) * time.Second)
+	case hasReset:
+		t.resetAt = time.Unix(int64(resetUnix), 0)
+	}
+	t.mu.Unlock()
+
+	return resp, nil
+}
+
+// LastSeen returns the rate limit remaining count and HTTP status code from
+// the most recent response, for logging alongside a check. remaining is -1
+// if no response has been observed yet.
+func (t *rateLimitTransport) LastSeen() (remaining, statusCode int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.remaining, t.statusCode
+}
+
+// waitIfThrottled blocks until the last observed rate limit window resets,
+// if the last response reported fewer than rateLimitThreshold requests left.
+func (t *rateLimitTransport) waitIfThrottled(ctx context.Context, logger *slog.Logger) {
+	t.mu.Lock()
+	remaining, resetAt := t.remaining, t.resetAt
+	t.mu.Unlock()
+
+	if remaining < 0 || remaining >= rateLimitThreshold || resetAt.IsZero() {
+		return
+	}
+
+	wait := time.Until(resetAt)
+	if wait <= 0 {
+		return
+	}
+
+	logger.Warn("github rate limit nearly exhausted, pausing until reset", "remaining", remaining, "reset_at", resetAt, "wait", wait.String())
+	select {
+	case <-time.After(wait):
+	case <-ctx.Done():
+	}
+}
+
+func parseIntHeader(v string) (int, bool) {
+	if v == "" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// forEachRepo runs fn for every repo in repos, at most concurrency at a
+// time, and waits for all of them to finish.
+func forEachRepo(repos []Repository, concurrency int, fn func(i int, repo Repository)) {
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency()
+	}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, repo := range repos {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, repo Repository) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i, repo)
+		}(i, repo)
+	}
+	wg.Wait()
+}
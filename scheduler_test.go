@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/wimwenigerkind/github-release-monitor/metrics"
+)
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestWithRetryRetriesTransientThenSucceeds(t *testing.T) {
+	attempts := 0
+	cfg := retryConfig{maxAttempts: 5, baseDelay: time.Millisecond, maxDelay: time.Millisecond}
+
+	err := withRetry(context.Background(), cfg, discardLogger(), "test", func() error {
+		attempts++
+		if attempts < 3 {
+			return errors.New("403 API rate limit exceeded")
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("withRetry returned error: %v", err)
+	}
+	if attempts != 3 {
+		t.Fatalf("expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestWithRetryGivesUpOnPermanentError(t *testing.T) {
+	attempts := 0
+	permanent := errors.New("invalid slug format: foo")
+	cfg := retryConfig{maxAttempts: 5, baseDelay: time.Millisecond, maxDelay: time.Millisecond}
+
+	err := withRetry(context.Background(), cfg, discardLogger(), "test", func() error {
+		attempts++
+		return permanent
+	})
+
+	if !errors.Is(err, permanent) {
+		t.Fatalf("expected permanent error to be returned, got %v", err)
+	}
+	if attempts != 1 {
+		t.Fatalf("expected no retries for a permanent error, got %d attempts", attempts)
+	}
+}
+
+func TestWithRetryStopsAtMaxAttempts(t *testing.T) {
+	attempts := 0
+	transient := errors.New("502 bad gateway")
+	cfg := retryConfig{maxAttempts: 3, baseDelay: time.Millisecond, maxDelay: time.Millisecond}
+
+	err := withRetry(context.Background(), cfg, discardLogger(), "test", func() error {
+		attempts++
+		return transient
+	})
+
+	if !errors.Is(err, transient) {
+		t.Fatalf("expected the last error to be returned, got %v", err)
+	}
+	if attempts != cfg.maxAttempts {
+		t.Fatalf("expected %d attempts, got %d", cfg.maxAttempts, attempts)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"forbidden", errors.New("non-200 OK status code: 403"), true},
+		{"too many requests", errors.New("429 Too Many Requests"), true},
+		{"bad gateway", errors.New("502 Bad Gateway"), true},
+		{"secondary rate limit", errors.New("you have exceeded a secondary rate limit"), true},
+		{"abuse detection", errors.New("triggered an abuse detection mechanism"), true},
+		{"invalid slug", errors.New("invalid slug format: foo"), false},
+		{"not found", errors.New("repository not found"), false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := isRetryable(c.err); got != c.want {
+				t.Errorf("isRetryable(%v) = %v, want %v", c.err, got, c.want)
+			}
+		})
+	}
+}
+
+// fakeRoundTripper returns a canned response for every request, so tests can
+// drive rateLimitTransport off of specific rate-limit headers without a real
+// GitHub API call.
+type fakeRoundTripper struct {
+	statusCode int
+	headers    map[string]string
+}
+
+func (f fakeRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	header := http.Header{}
+	for k, v := range f.headers {
+		header.Set(k, v)
+	}
+	return &http.Response{
+		StatusCode: f.statusCode,
+		Header:     header,
+		Body:       http.NoBody,
+	}, nil
+}
+
+func TestRateLimitTransportRecordsHeaders(t *testing.T) {
+	resetAt := time.Now().Add(time.Hour).Unix()
+	base := fakeRoundTripper{
+		statusCode: 403,
+		headers: map[string]string{
+			"X-RateLimit-Remaining": "5",
+			"X-RateLimit-Reset":     strconv.FormatInt(resetAt, 10),
+		},
+	}
+	transport := newRateLimitTransport(base, metrics.New())
+
+	resp, err := transport.RoundTrip(&http.Request{})
+	if err != nil {
+		t.Fatalf("RoundTrip returned error: %v", err)
+	}
+	if resp.StatusCode != 403 {
+		t.Fatalf("expected status 403, got %d", resp.StatusCode)
+	}
+
+	remaining, statusCode := transport.LastSeen()
+	if remaining != 5 {
+		t.Errorf("expected remaining 5, got %d", remaining)
+	}
+	if statusCode != 403 {
+		t.Errorf("expected statusCode 403, got %d", statusCode)
+	}
+}
+
+func TestRateLimitTransportWaitIfThrottledSkipsWhenNotLow(t *testing.T) {
+	transport := newRateLimitTransport(fakeRoundTripper{statusCode: 200}, metrics.New())
+	transport.remaining = rateLimitThreshold + 1
+	transport.resetAt = time.Now().Add(time.Hour)
+
+	start := time.Now()
+	transport.waitIfThrottled(context.Background(), discardLogger())
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Fatalf("expected waitIfThrottled to return immediately, took %s", elapsed)
+	}
+}
+
+func TestRateLimitTransportWaitIfThrottledWaitsUntilReset(t *testing.T) {
+	transport := newRateLimitTransport(fakeRoundTripper{statusCode: 200}, metrics.New())
+	transport.remaining = rateLimitThreshold - 1
+	wait := 50 * time.Millisecond
+	transport.resetAt = time.Now().Add(wait)
+
+	start := time.Now()
+	transport.waitIfThrottled(context.Background(), discardLogger())
+	if elapsed := time.Since(start); elapsed < wait {
+		t.Fatalf("expected waitIfThrottled to wait at least %s, waited %s", wait, elapsed)
+	}
+}
+
+func TestRateLimitTransportWaitIfThrottledHonorsContextCancellation(t *testing.T) {
+	transport := newRateLimitTransport(fakeRoundTripper{statusCode: 200}, metrics.New())
+	transport.remaining = rateLimitThreshold - 1
+	transport.resetAt = time.Now().Add(time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	transport.waitIfThrottled(ctx, discardLogger())
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected waitIfThrottled to return once ctx was done, took %s", elapsed)
+	}
+}
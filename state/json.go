@@ -0,0 +1,65 @@
+package state
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+)
+
+// jsonStore is a Store backed by a single JSON file, rewritten in full on
+// every RecordRelease. Fine for the handful-of-repos to low-hundreds scale
+// this tool targets.
+type jsonStore struct {
+	path string
+
+	mu      sync.Mutex
+	entries map[string]jsonEntry
+}
+
+type jsonEntry struct {
+	Tag         string    `json:"tag"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+func newJSONStore(path string) (*jsonStore, error) {
+	store := &jsonStore{path: path, entries: map[string]jsonEntry{}}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &store.entries); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+func (s *jsonStore) GetSeenTag(repo string) (string, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.entries[repo]
+	return entry.Tag, entry.PublishedAt, nil
+}
+
+func (s *jsonStore) RecordRelease(repo, tag string, publishedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[repo] = jsonEntry{Tag: tag, PublishedAt: publishedAt}
+
+	data, err := json.MarshalIndent(s.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0644)
+}
+
+func (s *jsonStore) Close() error {
+	return nil
+}
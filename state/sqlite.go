@@ -0,0 +1,65 @@
+package state
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// sqliteStore is a Store backed by SQLite via modernc.org/sqlite, a
+// CGO-free driver so the binary stays easy to cross-compile.
+type sqliteStore struct {
+	db *sql.DB
+}
+
+func newSQLiteStore(path string) (*sqliteStore, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening sqlite state store: %w", err)
+	}
+
+	const schema = `
+		CREATE TABLE IF NOT EXISTS releases (
+			repo         TEXT PRIMARY KEY,
+			tag          TEXT NOT NULL,
+			published_at TIMESTAMP NOT NULL
+		)`
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error creating sqlite schema: %w", err)
+	}
+
+	return &sqliteStore{db: db}, nil
+}
+
+func (s *sqliteStore) GetSeenTag(repo string) (string, time.Time, error) {
+	var tag string
+	var publishedAt time.Time
+
+	row := s.db.QueryRow(`SELECT tag, published_at FROM releases WHERE repo = ?`, repo)
+	err := row.Scan(&tag, &publishedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", time.Time{}, nil
+	}
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return tag, publishedAt, nil
+}
+
+func (s *sqliteStore) RecordRelease(repo, tag string, publishedAt time.Time) error {
+	const upsert = `
+		INSERT INTO releases (repo, tag, published_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT(repo) DO UPDATE SET tag = excluded.tag, published_at = excluded.published_at`
+	_, err := s.db.Exec(upsert, repo, tag, publishedAt)
+	return err
+}
+
+func (s *sqliteStore) Close() error {
+	return s.db.Close()
+}
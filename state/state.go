@@ -0,0 +1,44 @@
+// Package state persists observed release tags outside of config.yml, so
+// config can stay read-only and human-edited while the monitor's own
+// bookkeeping lives elsewhere. A store keeps the last seen tag per
+// repository and its publish time; it does not keep last-check timestamps
+// or a history of earlier releases.
+package state
+
+import (
+	"fmt"
+	"time"
+)
+
+// Store tracks the most recently seen release tag per repository.
+type Store interface {
+	// GetSeenTag returns the last tag recorded for repo via RecordRelease,
+	// and when it was published. Both are zero values if repo has never
+	// been recorded.
+	GetSeenTag(repo string) (tag string, publishedAt time.Time, err error)
+
+	// RecordRelease stores tag as the latest known release for repo.
+	RecordRelease(repo, tag string, publishedAt time.Time) error
+
+	// Close releases any resources held by the store.
+	Close() error
+}
+
+// New builds the Store described by backend, defaulting to "json" when
+// backend is empty. path defaults per backend when empty.
+func New(backend, path string) (Store, error) {
+	switch backend {
+	case "", "json":
+		if path == "" {
+			path = "state.json"
+		}
+		return newJSONStore(path)
+	case "sqlite":
+		if path == "" {
+			path = "state.db"
+		}
+		return newSQLiteStore(path)
+	default:
+		return nil, fmt.Errorf("unknown state backend %q", backend)
+	}
+}
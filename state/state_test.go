@@ -0,0 +1,172 @@
+package state
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewUnknownBackend(t *testing.T) {
+	if _, err := New("bogus", ""); err == nil {
+		t.Fatal("expected an error for an unknown backend")
+	}
+}
+
+// newStores builds one instance of every backend under test, each backed by
+// its own file under t.TempDir, so the Store contract can be exercised
+// identically across implementations.
+func newStores(t *testing.T) map[string]Store {
+	t.Helper()
+
+	dir := t.TempDir()
+	stores := map[string]Store{}
+
+	jsonStore, err := New("json", filepath.Join(dir, "state.json"))
+	if err != nil {
+		t.Fatalf("New(json) error: %v", err)
+	}
+	stores["json"] = jsonStore
+
+	sqliteStore, err := New("sqlite", filepath.Join(dir, "state.db"))
+	if err != nil {
+		t.Fatalf("New(sqlite) error: %v", err)
+	}
+	stores["sqlite"] = sqliteStore
+
+	return stores
+}
+
+func TestStoreGetSeenTagUnknownRepo(t *testing.T) {
+	for name, store := range newStores(t) {
+		t.Run(name, func(t *testing.T) {
+			defer store.Close()
+
+			tag, publishedAt, err := store.GetSeenTag("owner/repo")
+			if err != nil {
+				t.Fatalf("GetSeenTag returned error: %v", err)
+			}
+			if tag != "" || !publishedAt.IsZero() {
+				t.Errorf("GetSeenTag(unknown) = %q, %v, want zero values", tag, publishedAt)
+			}
+		})
+	}
+}
+
+func TestStoreRecordAndGetSeenTag(t *testing.T) {
+	for name, store := range newStores(t) {
+		t.Run(name, func(t *testing.T) {
+			defer store.Close()
+
+			published := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+			if err := store.RecordRelease("owner/repo", "v1.0.0", published); err != nil {
+				t.Fatalf("RecordRelease returned error: %v", err)
+			}
+
+			tag, publishedAt, err := store.GetSeenTag("owner/repo")
+			if err != nil {
+				t.Fatalf("GetSeenTag returned error: %v", err)
+			}
+			if tag != "v1.0.0" {
+				t.Errorf("GetSeenTag tag = %q, want v1.0.0", tag)
+			}
+			if !publishedAt.Equal(published) {
+				t.Errorf("GetSeenTag publishedAt = %v, want %v", publishedAt, published)
+			}
+		})
+	}
+}
+
+func TestStoreRecordReleaseOverwritesPreviousTag(t *testing.T) {
+	for name, store := range newStores(t) {
+		t.Run(name, func(t *testing.T) {
+			defer store.Close()
+
+			first := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+			second := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+
+			if err := store.RecordRelease("owner/repo", "v1.0.0", first); err != nil {
+				t.Fatalf("RecordRelease returned error: %v", err)
+			}
+			if err := store.RecordRelease("owner/repo", "v1.1.0", second); err != nil {
+				t.Fatalf("RecordRelease returned error: %v", err)
+			}
+
+			tag, publishedAt, err := store.GetSeenTag("owner/repo")
+			if err != nil {
+				t.Fatalf("GetSeenTag returned error: %v", err)
+			}
+			if tag != "v1.1.0" {
+				t.Errorf("GetSeenTag tag = %q, want v1.1.0", tag)
+			}
+			if !publishedAt.Equal(second) {
+				t.Errorf("GetSeenTag publishedAt = %v, want %v", publishedAt, second)
+			}
+		})
+	}
+}
+
+func TestStoreTracksMultipleRepos(t *testing.T) {
+	for name, store := range newStores(t) {
+		t.Run(name, func(t *testing.T) {
+			defer store.Close()
+
+			now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+			if err := store.RecordRelease("owner/one", "v1.0.0", now); err != nil {
+				t.Fatalf("RecordRelease returned error: %v", err)
+			}
+			if err := store.RecordRelease("owner/two", "v2.0.0", now); err != nil {
+				t.Fatalf("RecordRelease returned error: %v", err)
+			}
+
+			tag, _, err := store.GetSeenTag("owner/one")
+			if err != nil {
+				t.Fatalf("GetSeenTag returned error: %v", err)
+			}
+			if tag != "v1.0.0" {
+				t.Errorf("GetSeenTag(owner/one) = %q, want v1.0.0", tag)
+			}
+
+			tag, _, err = store.GetSeenTag("owner/two")
+			if err != nil {
+				t.Fatalf("GetSeenTag returned error: %v", err)
+			}
+			if tag != "v2.0.0" {
+				t.Errorf("GetSeenTag(owner/two) = %q, want v2.0.0", tag)
+			}
+		})
+	}
+}
+
+func TestJSONStorePersistsAcrossReload(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "state.json")
+
+	store, err := New("json", path)
+	if err != nil {
+		t.Fatalf("New(json) error: %v", err)
+	}
+	published := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := store.RecordRelease("owner/repo", "v1.0.0", published); err != nil {
+		t.Fatalf("RecordRelease returned error: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	reloaded, err := New("json", path)
+	if err != nil {
+		t.Fatalf("New(json) reload error: %v", err)
+	}
+	defer reloaded.Close()
+
+	tag, publishedAt, err := reloaded.GetSeenTag("owner/repo")
+	if err != nil {
+		t.Fatalf("GetSeenTag returned error: %v", err)
+	}
+	if tag != "v1.0.0" {
+		t.Errorf("GetSeenTag tag = %q, want v1.0.0", tag)
+	}
+	if !publishedAt.Equal(published) {
+		t.Errorf("GetSeenTag publishedAt = %v, want %v", publishedAt, published)
+	}
+}